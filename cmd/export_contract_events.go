@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/stellar-etl/internal/input"
+	"github.com/stellar/stellar-etl/internal/transform"
+	"github.com/stellar/stellar-etl/internal/utils"
+)
+
+var exportContractEventsCmd = &cobra.Command{
+	Use:   "export_contract_events",
+	Short: "This command exports the contract events and diagnostic events emitted by Soroban transactions.",
+	Long: `This command instantiates a stellar-core instance and uses it to export the contract events and diagnostic events
+emitted by Soroban transactions. The information is exported in batches determined by the batch-size flag, the same way
+export_ledger_entry_changes batches ledger entry changes.
+
+If the end-ledger is omitted, then the stellar-core node will continue running and exporting information as new ledgers
+are confirmed by the Stellar network.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		endNum, strictExport, isTest, extra := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		cmdLogger.StrictExport = strictExport
+		env := utils.GetEnvironmentDetails(isTest)
+		execPath, configPath, startNum, batchSize, outputFolder := utils.MustCoreFlags(cmd.Flags(), cmdLogger)
+
+		err := os.MkdirAll(outputFolder, os.ModePerm)
+		if err != nil {
+			cmdLogger.Fatalf("unable to mkdir %s: %v", outputFolder, err)
+		}
+
+		if batchSize <= 0 {
+			cmdLogger.Fatalf("batch-size (%d) must be greater than 0", batchSize)
+		}
+
+		if configPath == "" && endNum == 0 {
+			cmdLogger.Fatal("stellar-core needs a config file path when exporting ledgers continuously (endNum = 0)")
+		}
+
+		execPath, err = filepath.Abs(execPath)
+		if err != nil {
+			cmdLogger.Fatal("could not get absolute filepath for stellar-core executable: ", err)
+		}
+		configPath, err = filepath.Abs(configPath)
+		if err != nil {
+			cmdLogger.Fatal("could not get absolute filepath for the config file: ", err)
+		}
+
+		if endNum == 0 {
+			endNum = math.MaxInt32
+		}
+
+		core, err := input.PrepareCaptiveCore(execPath, configPath, startNum, endNum, env)
+		if err != nil {
+			cmdLogger.Fatal("error creating a prepared captive core instance: ", err)
+		}
+
+		ctx := context.Background()
+		for batchStart := startNum; batchStart <= endNum; batchStart += batchSize {
+			batchEnd := batchStart + batchSize - 1
+			if batchEnd > endNum {
+				batchEnd = endNum
+			}
+
+			var events []transform.ContractEventOutput
+			for ledgerSeq := batchStart; ledgerSeq <= batchEnd; ledgerSeq++ {
+				lcm, err := core.GetLedger(ctx, ledgerSeq)
+				if err != nil {
+					cmdLogger.LogError(err)
+					continue
+				}
+
+				reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, lcm)
+				if err != nil {
+					cmdLogger.LogError(err)
+					continue
+				}
+
+				for {
+					tx, err := reader.Read()
+					if err == ingest.ErrEOF {
+						break
+					}
+					if err != nil {
+						cmdLogger.LogError(err)
+						break
+					}
+
+					transformed, err := transform.TransformContractEvents(tx, lcm.LedgerHeaderHistoryEntry())
+					if err != nil {
+						cmdLogger.LogError(err)
+						continue
+					}
+					events = append(events, transformed...)
+				}
+				reader.Close()
+			}
+
+			path := filepath.Join(outputFolder, exportFilename(batchStart, batchEnd+1, "contract_events"))
+			outFile := mustOutFile(path)
+			for _, event := range events {
+				if _, err := exportEntry(event, outFile, extra); err != nil {
+					cmdLogger.LogError(err)
+				}
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportContractEventsCmd)
+	utils.AddCommonFlags(exportContractEventsCmd.Flags())
+	utils.AddCoreFlags(exportContractEventsCmd.Flags(), "contract_events_output/")
+
+	exportContractEventsCmd.MarkFlagRequired("start-ledger")
+	exportContractEventsCmd.MarkFlagRequired("core-executable")
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/xdr"
 	"github.com/stellar/stellar-etl/internal/input"
+	"github.com/stellar/stellar-etl/internal/output"
 	"github.com/stellar/stellar-etl/internal/transform"
 	"github.com/stellar/stellar-etl/internal/utils"
 	"github.com/stellar/stellar-etl/internal/utils/verify"
@@ -40,7 +42,34 @@ be exported.`,
 		// gcsBucket, gcpCredentials := utils.MustGcsFlags(cmd.Flags(), cmdLogger)
 		ctx := context.Background()
 
-		err := os.MkdirAll(outputFolder, os.ModePerm)
+		sinkKind, err := cmd.Flags().GetString("sink")
+		if err != nil {
+			cmdLogger.Fatal("could not get sink flag: ", err)
+		}
+		databaseURL, err := cmd.Flags().GetString("database-url")
+		if err != nil {
+			cmdLogger.Fatal("could not get database-url flag: ", err)
+		}
+
+		var sink output.Sink
+		switch sinkKind {
+		case "postgres":
+			sink = output.NewPostgresSink(databaseURL)
+		case "sqlite":
+			sink = output.NewSQLiteSink(databaseURL)
+		case "none":
+			sink = nil
+		default:
+			cmdLogger.Fatalf("unknown sink %q: must be one of postgres, sqlite, none", sinkKind)
+		}
+		if sink != nil {
+			if err := sink.Open(ctx); err != nil {
+				cmdLogger.Fatal("could not open sink: ", err)
+			}
+			defer sink.Close()
+		}
+
+		err = os.MkdirAll(outputFolder, os.ModePerm)
 		if err != nil {
 			cmdLogger.Fatalf("unable to mkdir %s: %v", outputFolder, err)
 		}
@@ -93,59 +122,59 @@ be exported.`,
 				var transformedOutputs transform.TransformedOutputType
 				for entryType, changes := range batch.Changes {
 					switch entryType {
-					// case xdr.LedgerEntryTypeAccount:
-					// 	for _, change := range changes {
-					// 		entry, _, _, _ := utils.ExtractEntryFromChange(change)
-					// 		if changed, err := change.AccountChangedExceptSigners(); err != nil {
-					// 			cmdLogger.LogError(fmt.Errorf("unable to identify changed accounts: %v", err))
-					// 			continue
-					// 		} else if changed {
-					// 			acc, err := transform.TransformAccount(change)
-					// 			if err != nil {
-					// 				cmdLogger.LogError(fmt.Errorf("error transforming account entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
-					// 				continue
-					// 			}
-					// 			transformedOutputs.Accounts = append(transformedOutputs.Accounts, acc)
-
-					// 			if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
-					// 				x := verifyOutputs[actualLedger]
-					// 				x.Accounts = append(x.Accounts, acc)
-					// 				verifyOutputs[actualLedger] = x
-					// 			}
-					// 		}
-					// 		if change.AccountSignersChanged() {
-					// 			signers, err := transform.TransformSigners(change)
-					// 			if err != nil {
-					// 				cmdLogger.LogError(fmt.Errorf("error transforming account signers from %d :%s", entry.LastModifiedLedgerSeq, err))
-					// 				continue
-					// 			}
-					// 			for _, s := range signers {
-					// 				transformedOutputs.Signers = append(transformedOutputs.Signers, s)
-
-					// 				if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
-					// 					x := verifyOutputs[actualLedger]
-					// 					x.Signers = append(x.Signers, s)
-					// 					verifyOutputs[actualLedger] = x
-					// 				}
-					// 			}
-					// 		}
-					// 	}
-					// case xdr.LedgerEntryTypeClaimableBalance:
-					// 	for _, change := range changes {
-					// 		entry, _, _, _ := utils.ExtractEntryFromChange(change)
-					// 		balance, err := transform.TransformClaimableBalance(change)
-					// 		if err != nil {
-					// 			cmdLogger.LogError(fmt.Errorf("error transforming balance entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
-					// 			continue
-					// 		}
-					// 		transformedOutputs.Claimable_balances = append(transformedOutputs.Claimable_balances, balance)
-
-					// 		if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
-					// 			x := verifyOutputs[actualLedger]
-					// 			x.Claimable_balances = append(x.Claimable_balances, balance)
-					// 			verifyOutputs[actualLedger] = x
-					// 		}
-					// 	}
+					case xdr.LedgerEntryTypeAccount:
+						for _, change := range changes {
+							entry, _, _, _ := utils.ExtractEntryFromChange(change)
+							if changed, err := change.AccountChangedExceptSigners(); err != nil {
+								cmdLogger.LogError(fmt.Errorf("unable to identify changed accounts: %v", err))
+								continue
+							} else if changed {
+								acc, err := transform.TransformAccount(change)
+								if err != nil {
+									cmdLogger.LogError(fmt.Errorf("error transforming account entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
+									continue
+								}
+								transformedOutputs.Accounts = append(transformedOutputs.Accounts, acc)
+
+								if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
+									x := verifyOutputs[actualLedger]
+									x.Accounts = append(x.Accounts, acc)
+									verifyOutputs[actualLedger] = x
+								}
+							}
+							if change.AccountSignersChanged() {
+								signers, err := transform.TransformSigners(change)
+								if err != nil {
+									cmdLogger.LogError(fmt.Errorf("error transforming account signers from %d :%s", entry.LastModifiedLedgerSeq, err))
+									continue
+								}
+								for _, s := range signers {
+									transformedOutputs.Signers = append(transformedOutputs.Signers, s)
+
+									if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
+										x := verifyOutputs[actualLedger]
+										x.Signers = append(x.Signers, s)
+										verifyOutputs[actualLedger] = x
+									}
+								}
+							}
+						}
+					case xdr.LedgerEntryTypeClaimableBalance:
+						for _, change := range changes {
+							entry, _, _, _ := utils.ExtractEntryFromChange(change)
+							balance, err := transform.TransformClaimableBalance(change)
+							if err != nil {
+								cmdLogger.LogError(fmt.Errorf("error transforming balance entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
+								continue
+							}
+							transformedOutputs.Claimable_balances = append(transformedOutputs.Claimable_balances, balance)
+
+							if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
+								x := verifyOutputs[actualLedger]
+								x.Claimable_balances = append(x.Claimable_balances, balance)
+								verifyOutputs[actualLedger] = x
+							}
+						}
 					case xdr.LedgerEntryTypeOffer:
 						for _, change := range changes {
 							entry, _, _, _ := utils.ExtractEntryFromChange(change)
@@ -162,38 +191,38 @@ be exported.`,
 								verifyOutputs[actualLedger] = x
 							}
 						}
-						// case xdr.LedgerEntryTypeTrustline:
-						// 	for _, change := range changes {
-						// 		entry, _, _, _ := utils.ExtractEntryFromChange(change)
-						// 		trust, err := transform.TransformTrustline(change)
-						// 		if err != nil {
-						// 			cmdLogger.LogError(fmt.Errorf("error transforming trustline entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
-						// 			continue
-						// 		}
-						// 		transformedOutputs.Trustlines = append(transformedOutputs.Trustlines, trust)
-
-						// 		if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
-						// 			x := verifyOutputs[actualLedger]
-						// 			x.Trustlines = append(x.Trustlines, trust)
-						// 			verifyOutputs[actualLedger] = x
-						// 		}
-						// 	}
-						// case xdr.LedgerEntryTypeLiquidityPool:
-						// 	for _, change := range changes {
-						// 		entry, _, _, _ := utils.ExtractEntryFromChange(change)
-						// 		pool, err := transform.TransformPool(change)
-						// 		if err != nil {
-						// 			cmdLogger.LogError(fmt.Errorf("error transforming liquidity pool entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
-						// 			continue
-						// 		}
-						// 		transformedOutputs.Liquidity_pools = append(transformedOutputs.Liquidity_pools, pool)
-
-						// 		if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
-						// 			x := verifyOutputs[actualLedger]
-						// 			x.Liquidity_pools = append(x.Liquidity_pools, pool)
-						// 			verifyOutputs[actualLedger] = x
-						// 		}
-						// 	}
+					case xdr.LedgerEntryTypeTrustline:
+						for _, change := range changes {
+							entry, _, _, _ := utils.ExtractEntryFromChange(change)
+							trust, err := transform.TransformTrustline(change)
+							if err != nil {
+								cmdLogger.LogError(fmt.Errorf("error transforming trustline entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
+								continue
+							}
+							transformedOutputs.Trustlines = append(transformedOutputs.Trustlines, trust)
+
+							if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
+								x := verifyOutputs[actualLedger]
+								x.Trustlines = append(x.Trustlines, trust)
+								verifyOutputs[actualLedger] = x
+							}
+						}
+					case xdr.LedgerEntryTypeLiquidityPool:
+						for _, change := range changes {
+							entry, _, _, _ := utils.ExtractEntryFromChange(change)
+							pool, err := transform.TransformPool(change)
+							if err != nil {
+								cmdLogger.LogError(fmt.Errorf("error transforming liquidity pool entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
+								continue
+							}
+							transformedOutputs.Liquidity_pools = append(transformedOutputs.Liquidity_pools, pool)
+
+							if ok, actualLedger := utils.LedgerIsCheckpoint(entry.LastModifiedLedgerSeq); ok {
+								x := verifyOutputs[actualLedger]
+								x.Liquidity_pools = append(x.Liquidity_pools, pool)
+								verifyOutputs[actualLedger] = x
+							}
+						}
 					}
 				}
 
@@ -202,15 +231,31 @@ be exported.`,
 					cmdLogger.LogError(err)
 					continue
 				}
+
+				if sink != nil {
+					if err := sink.UpsertLedgerEntryChanges(ctx, batch.BatchStart, transformedOutputs); err != nil {
+						cmdLogger.LogError(err)
+					}
+				}
 			}
 
-			for checkpointLedgers := range verifyOutputs {
-				v, err := verify.VerifyState(ctx, verifyOutputs[checkpointLedgers], archive, checkpointLedgers, verifyBatchSize)
+			for checkpointLedger, outputs := range verifyOutputs {
+				report, err := verify.VerifyState(ctx, outputs, archive, checkpointLedger, verifyBatchSize)
 				if err != nil {
-					panic(err)
+					cmdLogger.Fatal("error verifying state at checkpoint ", checkpointLedger, ": ", err)
+				}
+
+				reportJSON, err := json.Marshal(report)
+				if err != nil {
+					cmdLogger.Fatal("error marshaling verification report: ", err)
+				}
+				cmdLogger.Info(string(reportJSON))
+
+				if report.Diverged {
+					cmdLogger.Fatalf("state verification diverged from the history archive at checkpoint %d", checkpointLedger)
 				}
 
-				print(v)
+				delete(verifyOutputs, checkpointLedger)
 			}
 		}
 	},
@@ -252,6 +297,8 @@ func init() {
 	utils.AddCoreFlags(exportLedgerEntryChangesCmd.Flags(), "changes_output/")
 	utils.AddExportTypeFlags(exportLedgerEntryChangesCmd.Flags())
 	utils.AddGcsFlags(exportLedgerEntryChangesCmd.Flags())
+	exportLedgerEntryChangesCmd.Flags().String("sink", "none", "optional database sink for transformed ledger entry changes: postgres, sqlite, or none")
+	exportLedgerEntryChangesCmd.Flags().String("database-url", "", "connection string (postgres) or file path (sqlite) for --sink")
 
 	exportLedgerEntryChangesCmd.MarkFlagRequired("start-ledger")
 	exportLedgerEntryChangesCmd.MarkFlagRequired("core-executable")
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/stellar-etl/internal/input"
+	"github.com/stellar/stellar-etl/internal/output"
+	"github.com/stellar/stellar-etl/internal/serve"
+	"github.com/stellar/stellar-etl/internal/transform"
+	"github.com/stellar/stellar-etl/internal/utils"
+)
+
+var serveTransactionsCmd = &cobra.Command{
+	Use:   "serve_transactions",
+	Short: "This command runs captive-core and serves the resulting transactions over a getTransactions HTTP endpoint.",
+	Long: `This command instantiates a stellar-core instance, transforms the transactions in each closed ledger the same
+way export_transactions does, and keeps the most recent transaction-retention-window ledgers in memory so that they can
+be queried with a Soroban RPC getTransactions-style request: a POST body of {"method": "getTransactions", "params":
+{"startLedger": ..., "pagination": {"cursor": ..., "limit": ...}}} against --endpoint.
+
+The opaque cursor returned alongside each page is a base64-encoded TOID built from the ledger of the last transaction
+in that page and that transaction's zero-based position within the ledger, so a client can resume from the exact
+(ledger, transaction index) it left off at.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		endNum, strictExport, isTest, _ := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		cmdLogger.StrictExport = strictExport
+		env := utils.GetEnvironmentDetails(isTest)
+		execPath, configPath, startNum, _, _ := utils.MustCoreFlags(cmd.Flags(), cmdLogger)
+
+		endpoint, err := cmd.Flags().GetString("endpoint")
+		if err != nil {
+			cmdLogger.Fatal("could not get endpoint flag: ", err)
+		}
+		retentionWindow, err := cmd.Flags().GetUint32("transaction-retention-window")
+		if err != nil {
+			cmdLogger.Fatal("could not get transaction-retention-window flag: ", err)
+		}
+		sinkKind, err := cmd.Flags().GetString("sink")
+		if err != nil {
+			cmdLogger.Fatal("could not get sink flag: ", err)
+		}
+		databaseURL, err := cmd.Flags().GetString("database-url")
+		if err != nil {
+			cmdLogger.Fatal("could not get database-url flag: ", err)
+		}
+
+		var sink output.Sink
+		switch sinkKind {
+		case "postgres":
+			sink = output.NewPostgresSink(databaseURL)
+		case "sqlite":
+			sink = output.NewSQLiteSink(databaseURL)
+		case "none":
+			sink = nil
+		default:
+			cmdLogger.Fatalf("unknown sink %q: must be one of postgres, sqlite, none", sinkKind)
+		}
+		if sink != nil {
+			if err := sink.Open(context.Background()); err != nil {
+				cmdLogger.Fatal("could not open sink: ", err)
+			}
+			defer sink.Close()
+		}
+
+		if configPath == "" && endNum == 0 {
+			cmdLogger.Fatal("stellar-core needs a config file path when exporting ledgers continuously (endNum = 0)")
+		}
+
+		execPath, err = filepath.Abs(execPath)
+		if err != nil {
+			cmdLogger.Fatal("could not get absolute filepath for stellar-core executable: ", err)
+		}
+		configPath, err = filepath.Abs(configPath)
+		if err != nil {
+			cmdLogger.Fatal("could not get absolute filepath for the config file: ", err)
+		}
+
+		if endNum == 0 {
+			endNum = math.MaxInt32
+		}
+
+		core, err := input.PrepareCaptiveCore(execPath, configPath, startNum, endNum, env)
+		if err != nil {
+			cmdLogger.Fatal("error creating a prepared captive core instance: ", err)
+		}
+
+		index := serve.NewTransactionIndex(retentionWindow)
+		go func() {
+			cmdLogger.Info("serving getTransactions on ", endpoint)
+			if err := http.ListenAndServe(endpoint, serve.NewServer(index)); err != nil {
+				cmdLogger.Fatal("getTransactions server stopped: ", err)
+			}
+		}()
+
+		ctx := context.Background()
+		for ledgerSeq := startNum; ledgerSeq <= endNum; ledgerSeq++ {
+			lcm, err := core.GetLedger(ctx, ledgerSeq)
+			if err != nil {
+				cmdLogger.LogError(err)
+				continue
+			}
+
+			reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, lcm)
+			if err != nil {
+				cmdLogger.LogError(err)
+				continue
+			}
+
+			var transactions []transform.TransactionOutput
+			for {
+				tx, err := reader.Read()
+				if err == ingest.ErrEOF {
+					break
+				}
+				if err != nil {
+					cmdLogger.LogError(err)
+					break
+				}
+
+				transformed, err := transform.TransformTransaction(tx, lcm.LedgerHeaderHistoryEntry())
+				if err != nil {
+					cmdLogger.LogError(err)
+					continue
+				}
+				transactions = append(transactions, transformed)
+			}
+			reader.Close()
+
+			index.Add(uint32(ledgerSeq), transactions)
+
+			if sink != nil {
+				if err := sink.UpsertTransactions(ctx, transactions); err != nil {
+					cmdLogger.LogError(err)
+				}
+				if err := sink.PruneTransactions(ctx, uint32(ledgerSeq), retentionWindow); err != nil {
+					cmdLogger.LogError(err)
+				}
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveTransactionsCmd)
+	utils.AddCommonFlags(serveTransactionsCmd.Flags())
+	utils.AddCoreFlags(serveTransactionsCmd.Flags(), "serve_transactions_output/")
+	serveTransactionsCmd.Flags().String("endpoint", "localhost:8000", "the host:port the getTransactions HTTP endpoint listens on")
+	serveTransactionsCmd.Flags().Uint32("transaction-retention-window", 1440, "the number of recent ledgers whose transactions are kept queryable through getTransactions")
+	serveTransactionsCmd.Flags().String("sink", "none", "optional database sink for transformed transactions: postgres, sqlite, or none")
+	serveTransactionsCmd.Flags().String("database-url", "", "connection string (postgres) or file path (sqlite) for --sink")
+
+	serveTransactionsCmd.MarkFlagRequired("start-ledger")
+	serveTransactionsCmd.MarkFlagRequired("core-executable")
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/stellar-etl/internal/input"
+	"github.com/stellar/stellar-etl/internal/stream"
+	"github.com/stellar/stellar-etl/internal/transform"
+	"github.com/stellar/stellar-etl/internal/utils"
+)
+
+var streamTransactionsCmd = &cobra.Command{
+	Use:   "stream_transactions",
+	Short: "This command runs captive-core and publishes each ledger's transformed transactions to live subscribers.",
+	Long: `This command instantiates a stellar-core instance, transforms the transactions in each closed ledger the same
+way export_transactions does, and publishes them to whichever sinks are configured: NATS JetStream (--nats-url), Kafka
+(--kafka-brokers), and/or the built-in WebSocket server (--websocket-endpoint).
+
+The WebSocket server accepts a subscription as the first message on each connection:
+{"startLedger": ..., "cursor": ..., "filters": {"account": ..., "memoType": ..., "successful": ...}}. A reconnecting
+client can pass a cursor, the base64-encoded TOID of a ledger and a zero-based transaction position within it, to
+resume with the transaction after that position, as long as the ledger is still within --replay-buffer-ledgers of the
+current tip; a cold client omits the cursor and gives a startLedger instead. Messages sent to subscribers are the
+transactions themselves and carry no cursor.
+All three sinks provide at-least-once delivery: a publish only succeeds once the message is durably accepted by the
+broker, or for WebSocket, recorded in the replay buffer.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		endNum, strictExport, isTest, _ := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		cmdLogger.StrictExport = strictExport
+		env := utils.GetEnvironmentDetails(isTest)
+		execPath, configPath, startNum, _, _ := utils.MustCoreFlags(cmd.Flags(), cmdLogger)
+
+		natsURL, err := cmd.Flags().GetString("nats-url")
+		if err != nil {
+			cmdLogger.Fatal("could not get nats-url flag: ", err)
+		}
+		natsSubject, err := cmd.Flags().GetString("nats-subject")
+		if err != nil {
+			cmdLogger.Fatal("could not get nats-subject flag: ", err)
+		}
+		kafkaBrokers, err := cmd.Flags().GetString("kafka-brokers")
+		if err != nil {
+			cmdLogger.Fatal("could not get kafka-brokers flag: ", err)
+		}
+		kafkaTopic, err := cmd.Flags().GetString("kafka-topic")
+		if err != nil {
+			cmdLogger.Fatal("could not get kafka-topic flag: ", err)
+		}
+		websocketEndpoint, err := cmd.Flags().GetString("websocket-endpoint")
+		if err != nil {
+			cmdLogger.Fatal("could not get websocket-endpoint flag: ", err)
+		}
+		replayBufferLedgers, err := cmd.Flags().GetUint32("replay-buffer-ledgers")
+		if err != nil {
+			cmdLogger.Fatal("could not get replay-buffer-ledgers flag: ", err)
+		}
+
+		var sinks []stream.Sink
+		if natsURL != "" {
+			natsSink, err := stream.NewNATSSink(natsURL, natsSubject)
+			if err != nil {
+				cmdLogger.Fatal("could not create NATS sink: ", err)
+			}
+			sinks = append(sinks, natsSink)
+		}
+		if kafkaBrokers != "" {
+			sinks = append(sinks, stream.NewKafkaSink(strings.Split(kafkaBrokers, ","), kafkaTopic))
+		}
+		if websocketEndpoint != "" {
+			hub := stream.NewWebSocketHub(stream.NewReplayBuffer(replayBufferLedgers))
+			sinks = append(sinks, hub)
+			go func() {
+				cmdLogger.Info("serving transaction subscriptions on ", websocketEndpoint)
+				if err := http.ListenAndServe(websocketEndpoint, hub); err != nil {
+					cmdLogger.Fatal("websocket sink stopped: ", err)
+				}
+			}()
+		}
+		if len(sinks) == 0 {
+			cmdLogger.Fatal("stream_transactions needs at least one of --nats-url, --kafka-brokers, or --websocket-endpoint")
+		}
+		defer func() {
+			for _, sink := range sinks {
+				if err := sink.Close(); err != nil {
+					cmdLogger.LogError(err)
+				}
+			}
+		}()
+
+		if configPath == "" && endNum == 0 {
+			cmdLogger.Fatal("stellar-core needs a config file path when exporting ledgers continuously (endNum = 0)")
+		}
+
+		execPath, err = filepath.Abs(execPath)
+		if err != nil {
+			cmdLogger.Fatal("could not get absolute filepath for stellar-core executable: ", err)
+		}
+		configPath, err = filepath.Abs(configPath)
+		if err != nil {
+			cmdLogger.Fatal("could not get absolute filepath for the config file: ", err)
+		}
+
+		if endNum == 0 {
+			endNum = math.MaxInt32
+		}
+
+		core, err := input.PrepareCaptiveCore(execPath, configPath, startNum, endNum, env)
+		if err != nil {
+			cmdLogger.Fatal("error creating a prepared captive core instance: ", err)
+		}
+
+		ctx := context.Background()
+		for ledgerSeq := startNum; ledgerSeq <= endNum; ledgerSeq++ {
+			lcm, err := core.GetLedger(ctx, ledgerSeq)
+			if err != nil {
+				cmdLogger.LogError(err)
+				continue
+			}
+
+			reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, lcm)
+			if err != nil {
+				cmdLogger.LogError(err)
+				continue
+			}
+
+			var transactions []transform.TransactionOutput
+			for {
+				tx, err := reader.Read()
+				if err == ingest.ErrEOF {
+					break
+				}
+				if err != nil {
+					cmdLogger.LogError(err)
+					break
+				}
+
+				transformed, err := transform.TransformTransaction(tx, lcm.LedgerHeaderHistoryEntry())
+				if err != nil {
+					cmdLogger.LogError(err)
+					continue
+				}
+				transactions = append(transactions, transformed)
+			}
+			reader.Close()
+
+			for _, sink := range sinks {
+				if err := sink.Publish(ctx, uint32(ledgerSeq), transactions); err != nil {
+					cmdLogger.LogError(err)
+				}
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(streamTransactionsCmd)
+	utils.AddCommonFlags(streamTransactionsCmd.Flags())
+	utils.AddCoreFlags(streamTransactionsCmd.Flags(), "stream_transactions_output/")
+	streamTransactionsCmd.Flags().String("nats-url", "", "NATS server URL to publish transactions to over JetStream; empty disables the NATS sink")
+	streamTransactionsCmd.Flags().String("nats-subject", "stellar.transactions", "subject prefix for the NATS sink; each ledger publishes to <prefix>.<ledgerSeq>")
+	streamTransactionsCmd.Flags().String("kafka-brokers", "", "comma-separated Kafka broker addresses to publish transactions to; empty disables the Kafka sink")
+	streamTransactionsCmd.Flags().String("kafka-topic", "stellar-transactions", "topic for the Kafka sink")
+	streamTransactionsCmd.Flags().String("websocket-endpoint", "", "host:port to serve transaction subscriptions on; empty disables the built-in WebSocket sink")
+	streamTransactionsCmd.Flags().Uint32("replay-buffer-ledgers", 120, "number of recent ledgers the WebSocket sink keeps available for reconnecting subscribers to replay")
+
+	streamTransactionsCmd.MarkFlagRequired("start-ledger")
+	streamTransactionsCmd.MarkFlagRequired("core-executable")
+}
@@ -0,0 +1,107 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// entryTypeTables maps each TransformedOutputType field to the table that
+// stores it. The transformed shape of each entry type is owned by the
+// transform package, not this one, so rows keep the full entry as a JSON
+// payload alongside the typed, indexable columns entryKeySchemas extracts
+// from it.
+var entryTypeTables = map[string]string{
+	"Accounts":           "ledger_entry_accounts",
+	"Signers":            "ledger_entry_signers",
+	"Offers":             "ledger_entry_offers",
+	"Trustlines":         "ledger_entry_trustlines",
+	"Liquidity_pools":    "ledger_entry_liquidity_pools",
+	"Claimable_balances": "ledger_entry_claimable_balances",
+}
+
+// entryKeySchema describes how to derive a natural upsert key and an
+// account column from a transformed entry's JSON representation, without
+// this package needing to depend on the transform package's concrete
+// per-type struct fields.
+type entryKeySchema struct {
+	// keyFields are the JSON field names, in order, concatenated to form
+	// the row's unique entry_key. They identify the underlying ledger
+	// entry, not any particular version of it, so upserting a later
+	// version of the same entry replaces the row instead of duplicating it.
+	keyFields []string
+	// accountField is the JSON field name holding the entry's owning
+	// account, or "" if the entry type has no single owning account.
+	accountField string
+}
+
+var entryKeySchemas = map[string]entryKeySchema{
+	"Accounts":           {keyFields: []string{"account_id"}, accountField: "account_id"},
+	"Signers":            {keyFields: []string{"account_id", "signer"}, accountField: "account_id"},
+	"Offers":             {keyFields: []string{"offer_id"}, accountField: "seller_id"},
+	"Trustlines":         {keyFields: []string{"account_id", "asset_code", "asset_issuer"}, accountField: "account_id"},
+	"Liquidity_pools":    {keyFields: []string{"liquidity_pool_id"}},
+	"Claimable_balances": {keyFields: []string{"balance_id"}},
+}
+
+// entryIdentity extracts entry's natural upsert key and owning account (if
+// any) according to field, using entry's own JSON encoding so it works
+// without a compile-time dependency on the transform package's output
+// structs.
+func entryIdentity(field string, entry interface{}) (key string, account string, err error) {
+	schema, ok := entryKeySchemas[field]
+	if !ok {
+		return "", "", fmt.Errorf("no entryKeySchema registered for %s", field)
+	}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return "", "", err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", "", err
+	}
+
+	parts := make([]string, len(schema.keyFields))
+	for i, name := range schema.keyFields {
+		parts[i] = fmt.Sprintf("%v", fields[name])
+	}
+	if schema.accountField != "" {
+		account = fmt.Sprintf("%v", fields[schema.accountField])
+	}
+	return strings.Join(parts, ":"), account, nil
+}
+
+// Sink is a queryable destination for transformed exports, as an alternative
+// (or a complement) to the file-based output the exporters write by default.
+// Implementations are expected to upsert by primary key so that re-exporting
+// a ledger (e.g. after a captive-core restart) is idempotent.
+type Sink interface {
+	// Open establishes the connection and creates any tables/indexes that
+	// don't already exist.
+	Open(ctx context.Context) error
+
+	// UpsertTransactions writes a batch of transformed transactions,
+	// indexed by ledger_sequence, transaction_id, account, and created_at.
+	UpsertTransactions(ctx context.Context, transactions []transform.TransactionOutput) error
+
+	// UpsertLedgerEntryChanges writes a batch of transformed ledger entry
+	// changes, one table per entry type, keyed by each entry's own natural
+	// identity (see entryKeySchemas) so a later version of the same entry
+	// replaces its row instead of duplicating it. ledgerSequence is the
+	// batch's start ledger, recorded alongside each row so it can be pruned
+	// or range-queried later.
+	UpsertLedgerEntryChanges(ctx context.Context, ledgerSequence uint32, outputs transform.TransformedOutputType) error
+
+	// PruneTransactions deletes transaction rows older than
+	// retentionLedgers behind latestLedger. A retentionLedgers of 0 means
+	// no pruning.
+	PruneTransactions(ctx context.Context, latestLedger, retentionLedgers uint32) error
+
+	// Close releases the underlying connection.
+	Close() error
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// TestSQLiteSinkUpsertLedgerEntryChanges guards against the field loop in
+// UpsertLedgerEntryChanges silently skipping every entry type: each
+// TransformedOutputType field holds a concrete struct slice, not
+// []interface{}, so a type assertion against []interface{} always fails.
+func TestSQLiteSinkUpsertLedgerEntryChanges(t *testing.T) {
+	sink := NewSQLiteSink(filepath.Join(t.TempDir(), "sink.db"))
+	ctx := context.Background()
+	if err := sink.Open(ctx); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer sink.Close()
+
+	outputs := transform.TransformedOutputType{
+		Accounts: []transform.AccountOutput{
+			{AccountID: "GACCOUNT"},
+		},
+		Offers: []transform.OfferOutput{
+			{OfferID: 42, SellerID: "GSELLER"},
+		},
+		Trustlines: []transform.TrustlineOutput{
+			{AccountID: "GACCOUNT", AssetCode: "USD", AssetIssuer: "GISSUER"},
+		},
+	}
+
+	if err := sink.UpsertLedgerEntryChanges(ctx, 100, outputs); err != nil {
+		t.Fatalf("UpsertLedgerEntryChanges: %v", err)
+	}
+
+	for table, wantKey := range map[string]string{
+		"ledger_entry_accounts":   "GACCOUNT",
+		"ledger_entry_offers":     "42",
+		"ledger_entry_trustlines": "GACCOUNT:USD:GISSUER",
+	} {
+		var gotKey string
+		row := sink.db.QueryRow("SELECT entry_key FROM " + table)
+		if err := row.Scan(&gotKey); err != nil {
+			if err == sql.ErrNoRows {
+				t.Errorf("%s: no row written, UpsertLedgerEntryChanges is a no-op", table)
+				continue
+			}
+			t.Errorf("%s: %v", table, err)
+			continue
+		}
+		if gotKey != wantKey {
+			t.Errorf("%s: entry_key = %q, want %q", table, gotKey, wantKey)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package output
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// SQLiteSink persists transformed exports to a local SQLite database. It's
+// meant for single-node or development deployments where running a separate
+// Postgres instance isn't worth it.
+type SQLiteSink struct {
+	path string
+	db   *sql.DB
+}
+
+// NewSQLiteSink returns a Sink backed by the SQLite database at path. Open
+// must be called before use.
+func NewSQLiteSink(path string) *SQLiteSink {
+	return &SQLiteSink{path: path}
+}
+
+// Open connects to the database file and creates the tables/indexes this
+// sink needs if they don't already exist.
+func (s *SQLiteSink) Open(ctx context.Context) error {
+	db, err := sql.Open("sqlite3", s.path)
+	if err != nil {
+		return fmt.Errorf("opening sqlite sink at %s: %v", s.path, err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("connecting to sqlite sink at %s: %v", s.path, err)
+	}
+	s.db = db
+
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS transactions (
+			transaction_id   INTEGER PRIMARY KEY,
+			transaction_hash TEXT NOT NULL,
+			ledger_sequence  INTEGER NOT NULL,
+			account          TEXT NOT NULL,
+			created_at       DATETIME NOT NULL,
+			successful       BOOLEAN NOT NULL,
+			envelope_xdr     TEXT NOT NULL,
+			result_xdr       TEXT NOT NULL,
+			result_meta_xdr  TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS transactions_ledger_sequence_idx ON transactions (ledger_sequence);
+		CREATE INDEX IF NOT EXISTS transactions_account_idx ON transactions (account);
+		CREATE INDEX IF NOT EXISTS transactions_created_at_idx ON transactions (created_at);
+	`); err != nil {
+		return fmt.Errorf("creating transactions table: %v", err)
+	}
+
+	for _, table := range entryTypeTables {
+		stmt := fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				entry_key       TEXT PRIMARY KEY,
+				ledger_sequence INTEGER NOT NULL,
+				account         TEXT,
+				entry           TEXT NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS %s_ledger_sequence_idx ON %s (ledger_sequence);
+			CREATE INDEX IF NOT EXISTS %s_account_idx ON %s (account);
+		`, table, table, table, table, table)
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("creating %s table: %v", table, err)
+		}
+	}
+
+	return nil
+}
+
+// UpsertTransactions writes or replaces rows keyed by transaction_id.
+func (s *SQLiteSink) UpsertTransactions(ctx context.Context, transactions []transform.TransactionOutput) error {
+	for _, tx := range transactions {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO transactions (transaction_id, transaction_hash, ledger_sequence, account, created_at, successful, envelope_xdr, result_xdr, result_meta_xdr)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (transaction_id) DO UPDATE SET
+				transaction_hash = excluded.transaction_hash,
+				ledger_sequence  = excluded.ledger_sequence,
+				account          = excluded.account,
+				created_at       = excluded.created_at,
+				successful       = excluded.successful,
+				envelope_xdr     = excluded.envelope_xdr,
+				result_xdr       = excluded.result_xdr,
+				result_meta_xdr  = excluded.result_meta_xdr
+		`, tx.TransactionID, tx.TransactionHash, tx.LedgerSequence, tx.Account, tx.CreatedAt, tx.Successful, tx.TxEnvelope, tx.TxResult, tx.TxMeta)
+		if err != nil {
+			return fmt.Errorf("upserting transaction %d: %v", tx.TransactionID, err)
+		}
+	}
+	return nil
+}
+
+// UpsertLedgerEntryChanges writes each changed entry as a JSON row in its
+// type's table, keyed by the entry's own natural identity (see
+// entryKeySchemas) so re-exporting a ledger after a captive-core restart
+// replaces the existing row instead of duplicating it.
+func (s *SQLiteSink) UpsertLedgerEntryChanges(ctx context.Context, ledgerSequence uint32, outputs transform.TransformedOutputType) error {
+	values := reflect.ValueOf(outputs)
+	typesOf := values.Type()
+
+	for i := 0; i < values.NumField(); i++ {
+		field := typesOf.Field(i).Name
+		table, ok := entryTypeTables[field]
+		if !ok {
+			continue
+		}
+
+		fieldValue := values.Field(i)
+		if fieldValue.Kind() != reflect.Slice {
+			continue
+		}
+
+		for j := 0; j < fieldValue.Len(); j++ {
+			entry := fieldValue.Index(j).Interface()
+			key, account, err := entryIdentity(field, entry)
+			if err != nil {
+				return fmt.Errorf("deriving key for %s entry: %v", table, err)
+			}
+
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return fmt.Errorf("marshaling %s entry: %v", table, err)
+			}
+
+			stmt := fmt.Sprintf(`
+				INSERT INTO %s (entry_key, ledger_sequence, account, entry)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT (entry_key) DO UPDATE SET
+					ledger_sequence = excluded.ledger_sequence,
+					account         = excluded.account,
+					entry           = excluded.entry
+			`, table)
+			if _, err := s.db.ExecContext(ctx, stmt, key, ledgerSequence, account, string(data)); err != nil {
+				return fmt.Errorf("upserting %s entry %s: %v", table, key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// PruneTransactions deletes transactions older than retentionLedgers behind
+// latestLedger.
+func (s *SQLiteSink) PruneTransactions(ctx context.Context, latestLedger, retentionLedgers uint32) error {
+	if retentionLedgers == 0 || latestLedger <= retentionLedgers {
+		return nil
+	}
+
+	oldestRetained := latestLedger - retentionLedgers
+	_, err := s.db.ExecContext(ctx, `DELETE FROM transactions WHERE ledger_sequence < ?`, oldestRetained)
+	if err != nil {
+		return fmt.Errorf("pruning transactions older than ledger %d: %v", oldestRetained, err)
+	}
+	return nil
+}
+
+// Close releases the underlying connection.
+func (s *SQLiteSink) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
@@ -0,0 +1,35 @@
+package serve
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/stellar/stellar-etl/internal/toid"
+)
+
+// EncodeCursor packs a (ledgerSeq, txIndex) pair into the opaque, base64
+// cursor returned from and accepted by getTransactions. The TOID already
+// orders ledgers and transactions within a ledger, so resuming from a cursor
+// is just resuming from the transaction after the encoded TOID.
+func EncodeCursor(ledgerSeq, txIndex int32) string {
+	id := toid.New(ledgerSeq, txIndex, 0).ToInt64()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(id))
+	return base64.StdEncoding.EncodeToString(buf[:])
+}
+
+// DecodeCursor reverses EncodeCursor, returning the ledger sequence and
+// transaction index encoded in the TOID.
+func DecodeCursor(cursor string) (ledgerSeq, txIndex int32, err error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cursor %q is not valid base64: %v", cursor, err)
+	}
+	if len(raw) != 8 {
+		return 0, 0, fmt.Errorf("cursor %q does not decode to an 8-byte TOID", cursor)
+	}
+
+	id := toid.Parse(int64(binary.BigEndian.Uint64(raw)))
+	return id.LedgerSequence, id.TransactionOrder, nil
+}
@@ -0,0 +1,183 @@
+package serve
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 200
+)
+
+// TransactionIndex is an in-memory, retention-bounded index of transformed
+// transactions, grouped by ledger, that backs the getTransactions endpoint.
+// Ledgers older than the retention window are pruned on every Add call, the
+// same way Soroban RPC bounds its own transaction store.
+type TransactionIndex struct {
+	mu               sync.RWMutex
+	retentionLedgers uint32
+	ledgers          map[uint32][]TransactionInfo
+	order            []uint32 // ledger sequences in insertion order, ascending
+}
+
+// NewTransactionIndex creates an index that retains at most retentionLedgers
+// worth of ledgers at a time.
+func NewTransactionIndex(retentionLedgers uint32) *TransactionIndex {
+	return &TransactionIndex{
+		retentionLedgers: retentionLedgers,
+		ledgers:          make(map[uint32][]TransactionInfo),
+	}
+}
+
+// Add records the transformed transactions for a ledger and prunes any
+// ledgers that have fallen outside the retention window.
+func (idx *TransactionIndex) Add(ledgerSeq uint32, txs []transform.TransactionOutput) {
+	infos := make([]TransactionInfo, len(txs))
+	for i, tx := range txs {
+		status := "SUCCESS"
+		if !tx.Successful {
+			status = "FAILED"
+		}
+		infos[i] = TransactionInfo{
+			Status:           status,
+			ApplicationOrder: int32(i + 1),
+			FeeBump:          tx.FeeAccount != "",
+			EnvelopeXdr:      tx.TxEnvelope,
+			ResultXdr:        tx.TxResult,
+			ResultMetaXdr:    tx.TxMeta,
+			Ledger:           tx.LedgerSequence,
+			CreatedAt:        tx.CreatedAt.Unix(),
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, exists := idx.ledgers[ledgerSeq]; !exists {
+		idx.order = append(idx.order, ledgerSeq)
+	}
+	idx.ledgers[ledgerSeq] = infos
+
+	if idx.retentionLedgers == 0 || uint32(len(idx.order)) <= idx.retentionLedgers {
+		return
+	}
+	for uint32(len(idx.order)) > idx.retentionLedgers {
+		delete(idx.ledgers, idx.order[0])
+		idx.order = idx.order[1:]
+	}
+}
+
+// Bounds returns the oldest and latest ledgers currently retained.
+func (idx *TransactionIndex) Bounds() (oldest, latest uint32) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if len(idx.order) == 0 {
+		return 0, 0
+	}
+	return idx.order[0], idx.order[len(idx.order)-1]
+}
+
+// GetTransactions resolves a page of transactions starting at req.StartLedger,
+// or resuming from req.Pagination.Cursor when one is supplied.
+func (idx *TransactionIndex) GetTransactions(req GetTransactionsRequest) (GetTransactionsResponse, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	limit := uint(defaultPageLimit)
+	startLedger := req.StartLedger
+	startTxIndex := int32(-1)
+
+	if req.Pagination != nil {
+		if req.Pagination.Limit > 0 {
+			limit = req.Pagination.Limit
+		}
+		if req.Pagination.Cursor != "" {
+			ledgerSeq, txIndex, err := DecodeCursor(req.Pagination.Cursor)
+			if err != nil {
+				return GetTransactionsResponse{}, err
+			}
+			startLedger = uint32(ledgerSeq)
+			startTxIndex = txIndex
+		}
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	oldest, latest := boundsLocked(idx.order)
+	if len(idx.order) == 0 {
+		return GetTransactionsResponse{}, fmt.Errorf("no transactions are currently retained")
+	}
+	if startLedger < oldest {
+		return GetTransactionsResponse{}, fmt.Errorf("start ledger %d is before the oldest retained ledger %d", startLedger, oldest)
+	}
+	if startLedger > latest {
+		return GetTransactionsResponse{}, fmt.Errorf("start ledger %d is after the latest retained ledger %d", startLedger, latest)
+	}
+
+	var page []TransactionInfo
+	var lastLedger uint32
+	var lastTxIndex int32
+
+	ledgers := append([]uint32(nil), idx.order...)
+	sort.Slice(ledgers, func(i, j int) bool { return ledgers[i] < ledgers[j] })
+
+	for _, seq := range ledgers {
+		if seq < startLedger {
+			continue
+		}
+		for txIndex, info := range idx.ledgers[seq] {
+			if seq == startLedger && int32(txIndex) <= startTxIndex {
+				continue
+			}
+			if uint(len(page)) >= limit {
+				break
+			}
+			page = append(page, info)
+			lastLedger, lastTxIndex = seq, int32(txIndex)
+		}
+		if uint(len(page)) >= limit {
+			break
+		}
+	}
+
+	cursor := req.Pagination.cursorOrEmpty()
+	if len(page) > 0 {
+		cursor = EncodeCursor(int32(lastLedger), lastTxIndex)
+	}
+
+	return GetTransactionsResponse{
+		Transactions: page,
+		LatestLedger: latest,
+		OldestLedger: oldest,
+		Cursor:       cursor,
+	}, nil
+}
+
+func boundsLocked(order []uint32) (oldest, latest uint32) {
+	if len(order) == 0 {
+		return 0, 0
+	}
+	min, max := order[0], order[0]
+	for _, seq := range order {
+		if seq < min {
+			min = seq
+		}
+		if seq > max {
+			max = seq
+		}
+	}
+	return min, max
+}
+
+func (p *TransactionsPagination) cursorOrEmpty() string {
+	if p == nil {
+		return ""
+	}
+	return p.Cursor
+}
@@ -0,0 +1,70 @@
+package serve
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Server exposes the transaction index over HTTP, accepting a getTransactions
+// JSON-RPC request and returning the corresponding page of transactions.
+type Server struct {
+	index *TransactionIndex
+}
+
+// NewServer wires an HTTP handler on top of an existing TransactionIndex.
+func NewServer(index *TransactionIndex) *Server {
+	return &Server{index: index}
+}
+
+type jsonRPCRequest struct {
+	ID     interface{}            `json:"id"`
+	Method string                 `json:"method"`
+	Params GetTransactionsRequest `json:"params"`
+}
+
+type jsonRPCResponse struct {
+	ID     interface{}              `json:"id"`
+	Result *GetTransactionsResponse `json:"result,omitempty"`
+	Error  *jsonRPCError            `json:"error,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ServeHTTP implements http.Handler, dispatching getTransactions calls.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "getTransactions only accepts POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req jsonRPCRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONRPCError(w, nil, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.Method != "getTransactions" {
+		writeJSONRPCError(w, req.ID, http.StatusNotFound, "unknown method "+req.Method)
+		return
+	}
+
+	resp, err := s.index.GetTransactions(req.Params)
+	if err != nil {
+		writeJSONRPCError(w, req.ID, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, jsonRPCResponse{ID: req.ID, Result: &resp})
+}
+
+func writeJSONRPCError(w http.ResponseWriter, id interface{}, status int, message string) {
+	writeJSON(w, status, jsonRPCResponse{ID: id, Error: &jsonRPCError{Code: status, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,36 @@
+package serve
+
+// GetTransactionsRequest is the payload for a getTransactions call. StartLedger
+// is only honored when Pagination.Cursor is empty; once a cursor is supplied it
+// takes precedence, matching the getEvents/getTransactions convention used by
+// Soroban RPC.
+type GetTransactionsRequest struct {
+	StartLedger uint32                  `json:"startLedger"`
+	Pagination  *TransactionsPagination `json:"pagination,omitempty"`
+}
+
+// TransactionsPagination bounds a single getTransactions page.
+type TransactionsPagination struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+// TransactionInfo is a single transformed transaction as returned by getTransactions.
+type TransactionInfo struct {
+	Status           string `json:"status"`
+	ApplicationOrder int32  `json:"applicationOrder"`
+	FeeBump          bool   `json:"feeBump"`
+	EnvelopeXdr      string `json:"envelopeXdr"`
+	ResultXdr        string `json:"resultXdr"`
+	ResultMetaXdr    string `json:"resultMetaXdr"`
+	Ledger           uint32 `json:"ledger"`
+	CreatedAt        int64  `json:"createdAt"`
+}
+
+// GetTransactionsResponse is the getTransactions result set.
+type GetTransactionsResponse struct {
+	Transactions []TransactionInfo `json:"transactions"`
+	LatestLedger uint32            `json:"latestLedger"`
+	OldestLedger uint32            `json:"oldestLedger"`
+	Cursor       string            `json:"cursor"`
+}
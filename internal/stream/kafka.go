@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// KafkaSink publishes each transaction as a message keyed by ledger
+// sequence, partitioned by ledger sequence so a single ledger's
+// transactions land on the same partition and preserve their original
+// order.
+type KafkaSink struct {
+	topic  string
+	writer *kafka.Writer
+}
+
+// NewKafkaSink creates a writer for the given brokers and topic. RequiredAcks
+// is set to acks=all so Publish only returns once every in-sync replica has
+// the message, giving at-least-once delivery.
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		topic: topic,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+// Name implements Sink.
+func (s *KafkaSink) Name() string { return "kafka" }
+
+// Publish implements Sink.
+func (s *KafkaSink) Publish(ctx context.Context, ledgerSeq uint32, transactions []transform.TransactionOutput) error {
+	key := []byte(strconv.FormatUint(uint64(ledgerSeq), 10))
+	messages := make([]kafka.Message, len(transactions))
+	for i, tx := range transactions {
+		payload, err := json.Marshal(tx)
+		if err != nil {
+			return fmt.Errorf("marshaling transaction %d: %v", tx.TransactionID, err)
+		}
+		messages[i] = kafka.Message{
+			Key:   key,
+			Value: payload,
+		}
+	}
+
+	if err := s.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("writing ledger %d transactions to topic %s: %v", ledgerSeq, s.topic, err)
+	}
+	return nil
+}
+
+// Close implements Sink.
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
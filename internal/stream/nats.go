@@ -0,0 +1,70 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// NATSSink publishes each transaction to a JetStream subject, one message
+// per transaction, so a consumer's durable cursor tracks per-transaction
+// acks rather than per-ledger batches.
+type NATSSink struct {
+	subjectPrefix string
+	conn          *nats.Conn
+	js            nats.JetStreamContext
+}
+
+// NewNATSSink connects to NATS at url and ensures a JetStream stream exists
+// covering subjectPrefix.>.
+func NewNATSSink(url, subjectPrefix string) (*NATSSink, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to NATS at %s: %v", url, err)
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("creating JetStream context: %v", err)
+	}
+
+	if _, err := js.AddStream(&nats.StreamConfig{
+		Name:     "STELLAR_ETL_TRANSACTIONS",
+		Subjects: []string{subjectPrefix + ".>"},
+	}); err != nil && err != nats.ErrStreamNameAlreadyInUse {
+		conn.Close()
+		return nil, fmt.Errorf("creating JetStream stream: %v", err)
+	}
+
+	return &NATSSink{subjectPrefix: subjectPrefix, conn: conn, js: js}, nil
+}
+
+// Name implements Sink.
+func (s *NATSSink) Name() string { return "nats" }
+
+// Publish implements Sink. Each call blocks until JetStream acknowledges the
+// message has been persisted, giving at-least-once delivery on redelivery.
+func (s *NATSSink) Publish(ctx context.Context, ledgerSeq uint32, transactions []transform.TransactionOutput) error {
+	for _, tx := range transactions {
+		payload, err := json.Marshal(tx)
+		if err != nil {
+			return fmt.Errorf("marshaling transaction %d: %v", tx.TransactionID, err)
+		}
+
+		subject := fmt.Sprintf("%s.%d", s.subjectPrefix, ledgerSeq)
+		if _, err := s.js.Publish(subject, payload, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("publishing transaction %d to %s: %v", tx.TransactionID, subject, err)
+		}
+	}
+	return nil
+}
+
+// Close implements Sink.
+func (s *NATSSink) Close() error {
+	s.conn.Close()
+	return nil
+}
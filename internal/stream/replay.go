@@ -0,0 +1,76 @@
+package stream
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// ReplayBuffer retains the most recent bufferLedgers worth of transactions so
+// a reconnecting WebSocket subscriber can catch up to the current ledger
+// without captive-core having to be restarted or re-run.
+type ReplayBuffer struct {
+	mu            sync.RWMutex
+	bufferLedgers uint32
+	ledgers       map[uint32][]transform.TransactionOutput
+	order         []uint32
+}
+
+// NewReplayBuffer creates a buffer that retains at most bufferLedgers worth
+// of ledgers. A bufferLedgers of 0 means unbounded (not recommended for long
+// running streams).
+func NewReplayBuffer(bufferLedgers uint32) *ReplayBuffer {
+	return &ReplayBuffer{
+		bufferLedgers: bufferLedgers,
+		ledgers:       make(map[uint32][]transform.TransactionOutput),
+	}
+}
+
+// Add records a ledger's transactions and evicts ledgers that have fallen
+// outside the buffer window.
+func (b *ReplayBuffer) Add(ledgerSeq uint32, txs []transform.TransactionOutput) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.ledgers[ledgerSeq]; !exists {
+		b.order = append(b.order, ledgerSeq)
+	}
+	b.ledgers[ledgerSeq] = txs
+
+	if b.bufferLedgers == 0 || uint32(len(b.order)) <= b.bufferLedgers {
+		return
+	}
+	for uint32(len(b.order)) > b.bufferLedgers {
+		delete(b.ledgers, b.order[0])
+		b.order = b.order[1:]
+	}
+}
+
+// Since returns every transaction after (ledgerSeq, txIndex), in ledger then
+// transaction order, along with the latest ledger retained. Subscribers use
+// this to replay from their last acknowledged cursor after reconnecting.
+func (b *ReplayBuffer) Since(ledgerSeq uint32, txIndex int32) (txs []transform.TransactionOutput, latest uint32) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ledgers := append([]uint32(nil), b.order...)
+	sort.Slice(ledgers, func(i, j int) bool { return ledgers[i] < ledgers[j] })
+
+	for _, seq := range ledgers {
+		if seq > latest {
+			latest = seq
+		}
+		if seq < ledgerSeq {
+			continue
+		}
+		for i, tx := range b.ledgers[seq] {
+			if seq == ledgerSeq && int32(i) <= txIndex {
+				continue
+			}
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs, latest
+}
@@ -0,0 +1,23 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// Sink publishes each closed ledger's transformed transactions to a
+// downstream consumer. Implementations should provide at-least-once
+// delivery: a Publish call that returns a nil error means the message has
+// been durably accepted by the broker (or, for the built-in WebSocket
+// server, added to the replay buffer before fanning out to subscribers).
+type Sink interface {
+	// Name identifies the sink in logs, e.g. "nats", "kafka", "websocket".
+	Name() string
+
+	// Publish sends a ledger's transformed transactions downstream.
+	Publish(ctx context.Context, ledgerSeq uint32, transactions []transform.TransactionOutput) error
+
+	// Close releases any underlying connections.
+	Close() error
+}
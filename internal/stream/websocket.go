@@ -0,0 +1,209 @@
+package stream
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/stellar/stellar-etl/internal/serve"
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// SubscribeRequest is the first message a WebSocket client sends after
+// connecting, selecting where in the stream to resume and which
+// transactions it wants to receive.
+type SubscribeRequest struct {
+	StartLedger uint32              `json:"startLedger"`
+	Cursor      string              `json:"cursor,omitempty"`
+	Filters     SubscriptionFilters `json:"filters"`
+}
+
+// SubscriptionFilters narrows a subscription to transactions matching all of
+// the set fields.
+type SubscriptionFilters struct {
+	Account    string `json:"account,omitempty"`
+	MemoType   string `json:"memoType,omitempty"`
+	Successful *bool  `json:"successful,omitempty"`
+}
+
+// subscriberBufferSize bounds how far a slow WebSocket subscriber can fall
+// behind the live stream before newly published transactions are dropped
+// for it; it can still catch up from the replay buffer on reconnect.
+const subscriberBufferSize = 256
+
+type subscriber struct {
+	ch      chan transform.TransactionOutput
+	filters SubscriptionFilters
+	// closed is set under WebSocketHub.mu before ch is closed, so Publish can
+	// check it under the same lock it uses to snapshot subscribers and never
+	// send on a channel that's already been (or is about to be) closed.
+	closed bool
+}
+
+// WebSocketHub is the built-in Sink that accepts WebSocket subscriptions and
+// fans out newly transformed transactions to each matching subscriber,
+// backed by a ReplayBuffer so a reconnecting client can catch up.
+type WebSocketHub struct {
+	buffer      *ReplayBuffer
+	upgrader    websocket.Upgrader
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// NewWebSocketHub creates a hub backed by the given replay buffer.
+func NewWebSocketHub(buffer *ReplayBuffer) *WebSocketHub {
+	return &WebSocketHub{
+		buffer:      buffer,
+		subscribers: make(map[*subscriber]struct{}),
+		upgrader:    websocket.Upgrader{},
+	}
+}
+
+// Name implements Sink.
+func (h *WebSocketHub) Name() string { return "websocket" }
+
+// Publish implements Sink: it records the ledger in the replay buffer and
+// fans each matching transaction out to every live subscriber.
+func (h *WebSocketHub) Publish(ctx context.Context, ledgerSeq uint32, transactions []transform.TransactionOutput) error {
+	h.buffer.Add(ledgerSeq, transactions)
+
+	h.mu.Lock()
+	subs := make([]*subscriber, 0, len(h.subscribers))
+	for sub := range h.subscribers {
+		subs = append(subs, sub)
+	}
+	h.mu.Unlock()
+
+	for _, tx := range transactions {
+		for _, sub := range subs {
+			if !matchesFilter(tx, sub.filters) {
+				continue
+			}
+			h.sendLocked(sub, tx)
+		}
+	}
+
+	return nil
+}
+
+// sendLocked delivers tx to sub if it hasn't been closed out from under
+// Publish by a concurrent disconnect, dropping it if the subscriber's buffer
+// is full. The closed check and the send happen under the same lock
+// removeSubscriber and Close use to close sub.ch, so this can never send on
+// an already-closed channel.
+func (h *WebSocketHub) sendLocked(sub *subscriber, tx transform.TransactionOutput) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if sub.closed {
+		return
+	}
+	select {
+	case sub.ch <- tx:
+	default:
+		// The subscriber is falling behind; drop rather than block the
+		// exporter loop. It can catch up from the replay buffer by
+		// reconnecting with a cursor.
+	}
+}
+
+// Close implements Sink.
+func (h *WebSocketHub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		sub.closed = true
+		close(sub.ch)
+	}
+	h.subscribers = make(map[*subscriber]struct{})
+	return nil
+}
+
+// ServeHTTP upgrades the connection, replays everything since the
+// subscriber's requested cursor, then streams new matching transactions as
+// they're published.
+func (h *WebSocketHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var req SubscribeRequest
+	if err := conn.ReadJSON(&req); err != nil {
+		return
+	}
+
+	startLedger := req.StartLedger
+	startTxIndex := int32(-1)
+	if req.Cursor != "" {
+		ledgerSeq, txIndex, err := serve.DecodeCursor(req.Cursor)
+		if err != nil {
+			conn.WriteJSON(map[string]string{"error": err.Error()})
+			return
+		}
+		startLedger, startTxIndex = uint32(ledgerSeq), txIndex
+	}
+
+	// Register before snapshotting the replay buffer, not after: if it were
+	// the other way around, a ledger Published in between the snapshot and
+	// the registration would be in neither and would be silently lost. Any
+	// transaction published in that window is now guaranteed to reach this
+	// subscriber - via the replay snapshot, via sub.ch, or (if Publish ran
+	// mid-snapshot) both - so replayedIDs dedupes the live feed against what
+	// was already sent during replay.
+	sub := &subscriber{ch: make(chan transform.TransactionOutput, subscriberBufferSize), filters: req.Filters}
+	h.addSubscriber(sub)
+	defer h.removeSubscriber(sub)
+
+	replay, _ := h.buffer.Since(startLedger, startTxIndex)
+	replayedIDs := make(map[int64]bool, len(replay))
+	for _, tx := range replay {
+		replayedIDs[tx.TransactionID] = true
+		if !matchesFilter(tx, req.Filters) {
+			continue
+		}
+		if err := conn.WriteJSON(tx); err != nil {
+			return
+		}
+	}
+
+	for tx := range sub.ch {
+		if replayedIDs[tx.TransactionID] {
+			delete(replayedIDs, tx.TransactionID)
+			continue
+		}
+		if err := conn.WriteJSON(tx); err != nil {
+			return
+		}
+	}
+}
+
+func (h *WebSocketHub) addSubscriber(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subscribers[sub] = struct{}{}
+}
+
+func (h *WebSocketHub) removeSubscriber(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, sub)
+	if !sub.closed {
+		sub.closed = true
+		close(sub.ch)
+	}
+}
+
+func matchesFilter(tx transform.TransactionOutput, filters SubscriptionFilters) bool {
+	if filters.Account != "" && tx.Account != filters.Account {
+		return false
+	}
+	if filters.MemoType != "" && tx.MemoType != filters.MemoType {
+		return false
+	}
+	if filters.Successful != nil && tx.Successful != *filters.Successful {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,201 @@
+package transform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+	"github.com/stellar/stellar-etl/internal/toid"
+	"github.com/stellar/stellar-etl/internal/utils"
+)
+
+// ContractEventOutput is a single contract or diagnostic event emitted by a
+// Soroban transaction, transformed for ingestion the same way TransactionOutput
+// is.
+type ContractEventOutput struct {
+	TransactionID            int64          `json:"transaction_id"`
+	EventIndex               int32          `json:"event_index"`
+	InSuccessfulContractCall bool           `json:"in_successful_contract_call"`
+	ContractID               string         `json:"contract_id,omitempty"`
+	EventType                string         `json:"event_type"`
+	TopicsXDR                pq.StringArray `json:"topics_xdr"`
+	TopicsDecoded            pq.StringArray `json:"topics_decoded"`
+	DataXDR                  string         `json:"data_xdr"`
+	DataDecoded              string         `json:"data_decoded"`
+	LedgerClosedAt           time.Time      `json:"ledger_closed_at"`
+}
+
+// TransformContractEvents walks the contract events and diagnostic events
+// recorded for a Soroban transaction and returns one ContractEventOutput per
+// event: all contract events first, then all diagnostic events, each group in
+// the order it was recorded, with event_index counting across both groups.
+// It returns an empty slice for
+// classic transactions or Soroban transactions with no recorded events.
+func TransformContractEvents(transaction ingest.LedgerTransaction, lhe xdr.LedgerHeaderHistoryEntry) ([]ContractEventOutput, error) {
+	ledgerHeader := lhe.Header
+	outputTransactionID := toid.New(int32(ledgerHeader.LedgerSeq), int32(transaction.Index), 0).ToInt64()
+
+	outputCreatedAt, err := utils.TimePointToUTCTimeStamp(ledgerHeader.ScpValue.CloseTime)
+	if err != nil {
+		return nil, fmt.Errorf("for transaction id=%d: %v", outputTransactionID, err)
+	}
+
+	sorobanMeta, ok := sorobanTransactionMeta(transaction.UnsafeMeta)
+	if !ok {
+		return nil, nil
+	}
+
+	var outputs []ContractEventOutput
+	eventIndex := int32(0)
+
+	for _, event := range sorobanMeta.Events {
+		output, err := transformContractEvent(event, true, outputTransactionID, eventIndex, outputCreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("for transaction id=%d, event %d: %v", outputTransactionID, eventIndex, err)
+		}
+		outputs = append(outputs, output)
+		eventIndex++
+	}
+
+	for _, diagnosticEvent := range sorobanMeta.DiagnosticEvents {
+		output, err := transformContractEvent(diagnosticEvent.Event, diagnosticEvent.InSuccessfulContractCall, outputTransactionID, eventIndex, outputCreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("for transaction id=%d, diagnostic event %d: %v", outputTransactionID, eventIndex, err)
+		}
+		outputs = append(outputs, output)
+		eventIndex++
+	}
+
+	return outputs, nil
+}
+
+func transformContractEvent(event xdr.ContractEvent, inSuccessfulContractCall bool, transactionID int64, eventIndex int32, createdAt time.Time) (ContractEventOutput, error) {
+	output := ContractEventOutput{
+		TransactionID:            transactionID,
+		EventIndex:               eventIndex,
+		InSuccessfulContractCall: inSuccessfulContractCall,
+		EventType:                event.Type.String(),
+		LedgerClosedAt:           createdAt,
+	}
+
+	if event.ContractId != nil {
+		contractID, err := strkey.Encode(strkey.VersionByteContract, (*event.ContractId)[:])
+		if err != nil {
+			return ContractEventOutput{}, fmt.Errorf("encoding contract id: %v", err)
+		}
+		output.ContractID = contractID
+	}
+
+	body, ok := event.Body.GetV0()
+	if !ok {
+		return ContractEventOutput{}, fmt.Errorf("unsupported contract event body version %d", event.Body.V)
+	}
+
+	output.TopicsXDR = make(pq.StringArray, len(body.Topics))
+	output.TopicsDecoded = make(pq.StringArray, len(body.Topics))
+	for i, topic := range body.Topics {
+		topicXDR, err := xdr.MarshalBase64(&topic)
+		if err != nil {
+			return ContractEventOutput{}, fmt.Errorf("marshaling topic %d: %v", i, err)
+		}
+		output.TopicsXDR[i] = topicXDR
+		output.TopicsDecoded[i] = scValToJSON(topic)
+	}
+
+	dataXDR, err := xdr.MarshalBase64(&body.Data)
+	if err != nil {
+		return ContractEventOutput{}, fmt.Errorf("marshaling event data: %v", err)
+	}
+	output.DataXDR = dataXDR
+	output.DataDecoded = scValToJSON(body.Data)
+
+	return output, nil
+}
+
+// scValToJSON attempts to coerce an ScVal into a JSON representation of the
+// common Soroban types (Symbol, String, integers, Address, Bytes). Variants
+// it doesn't recognize fall back to a {"xdr": "<base64-encoded XDR>"} object
+// so no data is lost.
+func scValToJSON(val xdr.ScVal) string {
+	decoded, ok := decodeScVal(val)
+	if !ok {
+		raw, err := xdr.MarshalBase64(&val)
+		if err != nil {
+			return ""
+		}
+		return jsonString(map[string]string{"xdr": raw})
+	}
+
+	return jsonString(decoded)
+}
+
+func decodeScVal(val xdr.ScVal) (decoded interface{}, ok bool) {
+	defer func() {
+		// The Must* accessors panic on a type/arm mismatch; treat that as an
+		// unrecognized variant rather than letting it crash the exporter.
+		if r := recover(); r != nil {
+			decoded, ok = nil, false
+		}
+	}()
+
+	switch val.Type {
+	case xdr.ScValTypeScvSymbol:
+		return string(val.MustSym()), true
+	case xdr.ScValTypeScvString:
+		return string(val.MustStr()), true
+	case xdr.ScValTypeScvU64:
+		return uint64(val.MustU64()), true
+	case xdr.ScValTypeScvI64:
+		return int64(val.MustI64()), true
+	case xdr.ScValTypeScvU128:
+		parts := val.MustU128()
+		return combineUint128(uint64(parts.Hi), uint64(parts.Lo)).String(), true
+	case xdr.ScValTypeScvI128:
+		parts := val.MustI128()
+		return combineInt128(int64(parts.Hi), uint64(parts.Lo)).String(), true
+	case xdr.ScValTypeScvBytes:
+		return base64.StdEncoding.EncodeToString(val.MustBytes()), true
+	case xdr.ScValTypeScvAddress:
+		address := val.MustAddress()
+		switch address.Type {
+		case xdr.ScAddressTypeScAddressTypeAccount:
+			return address.MustAccountId().Address(), true
+		case xdr.ScAddressTypeScAddressTypeContract:
+			contractID, err := strkey.Encode(strkey.VersionByteContract, (*address.ContractId)[:])
+			if err != nil {
+				return nil, false
+			}
+			return contractID, true
+		}
+	}
+
+	return nil, false
+}
+
+func combineUint128(hi, lo uint64) *big.Int {
+	result := new(big.Int).SetUint64(hi)
+	result.Lsh(result, 64)
+	result.Or(result, new(big.Int).SetUint64(lo))
+	return result
+}
+
+func combineInt128(hi int64, lo uint64) *big.Int {
+	result := big.NewInt(hi)
+	result.Lsh(result, 64)
+	result.Or(result, new(big.Int).SetUint64(lo))
+	return result
+}
+
+func jsonString(v interface{}) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
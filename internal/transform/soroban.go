@@ -0,0 +1,64 @@
+package transform
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// sorobanTransactionData returns the SorobanTransactionData carried on the
+// envelope's V1 ext, looking through a fee bump's inner transaction if
+// necessary. ok is false for classic (pre-Soroban) transactions.
+func sorobanTransactionData(envelope xdr.TransactionEnvelope) (data xdr.SorobanTransactionData, ok bool) {
+	var tx xdr.Transaction
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		tx = envelope.V1.Tx
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		tx = envelope.FeeBump.Tx.InnerTx.V1.Tx
+	default:
+		return xdr.SorobanTransactionData{}, false
+	}
+
+	if tx.Ext.V != 1 || tx.Ext.SorobanData == nil {
+		return xdr.SorobanTransactionData{}, false
+	}
+	return *tx.Ext.SorobanData, true
+}
+
+// sorobanTransactionMaxFee returns the max fee bid of the transaction that
+// carries the SorobanTransactionData, looking through a fee bump's inner
+// transaction the same way sorobanTransactionData does. For a fee-bump
+// envelope this is the inner transaction's own fee bid, not the outer
+// bump fee envelope.Fee() would return, so it stays on the same side of
+// the fee-bump boundary as the resource fee it's compared against.
+func sorobanTransactionMaxFee(envelope xdr.TransactionEnvelope) int64 {
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		return int64(envelope.V1.Tx.Fee)
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		return int64(envelope.FeeBump.Tx.InnerTx.V1.Tx.Fee)
+	default:
+		return 0
+	}
+}
+
+// sorobanTransactionMeta returns the SorobanTransactionMeta from a
+// TransactionMetaV3, if present. ok is false for classic transactions, or
+// for Soroban transactions that failed before meta was recorded.
+func sorobanTransactionMeta(meta xdr.TransactionMeta) (sorobanMeta xdr.SorobanTransactionMeta, ok bool) {
+	if meta.V != 3 || meta.V3 == nil || meta.V3.SorobanMeta == nil {
+		return xdr.SorobanTransactionMeta{}, false
+	}
+	return *meta.V3.SorobanMeta, true
+}
+
+// sorobanRefundableFees splits the portion of FeeCharged spent on resources
+// into its non-refundable and refundable components. Protocol 20 refunds the
+// unused part of the refundable resource fee bid, so FeeCharged for a Soroban
+// transaction is inclusionFeeCharged + nonRefundable + refundable, not
+// inclusionFeeCharged + the full resource fee bid.
+func sorobanRefundableFees(sorobanMeta xdr.SorobanTransactionMeta) (nonRefundable, refundable int64) {
+	if sorobanMeta.Ext.V != 1 || sorobanMeta.Ext.V1 == nil {
+		return 0, 0
+	}
+	return int64(sorobanMeta.Ext.V1.TotalNonRefundableResourceFeeCharged), int64(sorobanMeta.Ext.V1.TotalRefundableResourceFeeCharged)
+}
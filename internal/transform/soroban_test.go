@@ -0,0 +1,212 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+func TestSorobanRefundableFees(t *testing.T) {
+	tests := []struct {
+		name              string
+		meta              xdr.SorobanTransactionMeta
+		wantNonRefundable int64
+		wantRefundable    int64
+	}{
+		{
+			name: "classic meta with no soroban extension",
+			meta: xdr.SorobanTransactionMeta{},
+		},
+		{
+			name: "soroban meta refunds the unused resource fee",
+			meta: xdr.SorobanTransactionMeta{
+				Ext: xdr.SorobanTransactionMetaExt{
+					V: 1,
+					V1: &xdr.SorobanTransactionMetaExtV1{
+						TotalNonRefundableResourceFeeCharged: 8000,
+						TotalRefundableResourceFeeCharged:    1500,
+					},
+				},
+			},
+			wantNonRefundable: 8000,
+			wantRefundable:    1500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotNonRefundable, gotRefundable := sorobanRefundableFees(tt.meta)
+			if gotNonRefundable != tt.wantNonRefundable {
+				t.Errorf("non-refundable fee = %d, want %d", gotNonRefundable, tt.wantNonRefundable)
+			}
+			if gotRefundable != tt.wantRefundable {
+				t.Errorf("refundable fee = %d, want %d", gotRefundable, tt.wantRefundable)
+			}
+		})
+	}
+}
+
+// TestFeeChargedAccounting verifies the relationship the exporter relies on:
+// the fee actually charged for a Soroban transaction is the inclusion fee
+// charged plus the resource fee actually charged (non-refundable +
+// refundable), which can be less than the resource fee bid in the envelope.
+// It exercises TransformTransaction directly, rather than re-deriving the
+// arithmetic by hand, so a wiring bug in its Soroban branch (e.g. swapped
+// InclusionFeeCharged/RefundableFeeCharged assignments) would fail it. It
+// covers both a direct envelope and a fee-bump wrapping one, since
+// InclusionFeeBid must come from the inner transaction's fee bid in both
+// cases, not the fee-bump's outer bump fee.
+func TestFeeChargedAccounting(t *testing.T) {
+	const (
+		testAccount = "GAQAA5L65LSYH7CQ3VTJ7F3HHNTNKPR3BIH7YHZJ6ECWO2NMYSIHN4JP"
+		feeSource   = "GDQP2KPQGKIHYJGXNUIYOMHARUARCA7DJT5FO2FFOOKY3B2WSQHG4W37"
+		innerMaxFee = int64(10_100)
+		bumpFee     = int64(50_000)
+		resourceFee = int64(10_000)
+		feeCharged  = int64(9_600)
+	)
+
+	sorobanData := xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{
+			Instructions: 1_000_000,
+			ReadBytes:    2_048,
+			WriteBytes:   512,
+		},
+		ResourceFee: xdr.Int64(resourceFee),
+	}
+
+	// TransformTransaction marshals the meta to XDR, so every union in it
+	// needs its arm set: a zero ScVal or ContractEventBody can't be encoded.
+	event := xdr.ContractEvent{
+		Type: xdr.ContractEventTypeContract,
+		Body: xdr.ContractEventBody{
+			V:  0,
+			V0: &xdr.ContractEventV0{Data: xdr.ScVal{Type: xdr.ScValTypeScvVoid}},
+		},
+	}
+
+	sorobanMeta := xdr.SorobanTransactionMeta{
+		Ext: xdr.SorobanTransactionMetaExt{
+			V: 1,
+			V1: &xdr.SorobanTransactionMetaExtV1{
+				TotalNonRefundableResourceFeeCharged: 8000,
+				TotalRefundableResourceFeeCharged:    1500,
+			},
+		},
+		Events:      []xdr.ContractEvent{event, event},
+		ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+	}
+
+	innerEnvelope := xdr.TransactionV1Envelope{
+		Tx: xdr.Transaction{
+			SourceAccount: xdr.MustAddress(testAccount).ToMuxedAccount(),
+			Fee:           xdr.Uint32(innerMaxFee),
+			SeqNum:        1,
+			Ext: xdr.TransactionExt{
+				V:           1,
+				SorobanData: &sorobanData,
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		envelope xdr.TransactionEnvelope
+		result   xdr.TransactionResultResult
+	}{
+		{
+			name: "direct envelope",
+			envelope: xdr.TransactionEnvelope{
+				Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+				V1:   &innerEnvelope,
+			},
+			result: xdr.TransactionResultResult{
+				Code:    xdr.TransactionResultCodeTxSuccess,
+				Results: &[]xdr.OperationResult{},
+			},
+		},
+		{
+			name: "fee-bump envelope",
+			envelope: xdr.TransactionEnvelope{
+				Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+				FeeBump: &xdr.FeeBumpTransactionEnvelope{
+					Tx: xdr.FeeBumpTransaction{
+						FeeSource: xdr.MustAddress(feeSource).ToMuxedAccount(),
+						Fee:       xdr.Int64(bumpFee),
+						InnerTx: xdr.FeeBumpTransactionInnerTx{
+							Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+							V1:   &innerEnvelope,
+						},
+					},
+				},
+			},
+			// TransformTransaction reads the inner transaction's hash from a
+			// fee-bump result, so it needs the inner result pair.
+			result: xdr.TransactionResultResult{
+				Code: xdr.TransactionResultCodeTxFeeBumpInnerSuccess,
+				InnerResultPair: &xdr.InnerTransactionResultPair{
+					Result: xdr.InnerTransactionResult{
+						FeeCharged: xdr.Int64(feeCharged),
+						Result: xdr.InnerTransactionResultResult{
+							Code:    xdr.TransactionResultCodeTxSuccess,
+							Results: &[]xdr.OperationResult{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := ingest.LedgerTransaction{
+				Index:    1,
+				Envelope: tt.envelope,
+				Result: xdr.TransactionResultPair{
+					Result: xdr.TransactionResult{
+						FeeCharged: xdr.Int64(feeCharged),
+						Result:     tt.result,
+					},
+				},
+				UnsafeMeta: xdr.TransactionMeta{
+					V:  3,
+					V3: &xdr.TransactionMetaV3{SorobanMeta: &sorobanMeta},
+				},
+			}
+
+			lhe := xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{LedgerSeq: 12345},
+			}
+
+			got, err := TransformTransaction(tx, lhe)
+			if err != nil {
+				t.Fatalf("TransformTransaction returned an error: %v", err)
+			}
+
+			nonRefundable, refundable := sorobanRefundableFees(sorobanMeta)
+			wantInclusionFeeBid := innerMaxFee - resourceFee
+			wantInclusionFeeCharged := feeCharged - nonRefundable - refundable
+
+			if !got.ResourceFee.Valid || got.ResourceFee.Int64 != resourceFee {
+				t.Errorf("ResourceFee = %v, want %d", got.ResourceFee, resourceFee)
+			}
+			if !got.InclusionFeeBid.Valid || got.InclusionFeeBid.Int64 != wantInclusionFeeBid {
+				t.Errorf("InclusionFeeBid = %v, want %d", got.InclusionFeeBid, wantInclusionFeeBid)
+			}
+			if !got.RefundableFeeCharged.Valid || got.RefundableFeeCharged.Int64 != refundable {
+				t.Errorf("RefundableFeeCharged = %v, want %d", got.RefundableFeeCharged, refundable)
+			}
+			if !got.InclusionFeeCharged.Valid || got.InclusionFeeCharged.Int64 != wantInclusionFeeCharged {
+				t.Errorf("InclusionFeeCharged = %v, want %d", got.InclusionFeeCharged, wantInclusionFeeCharged)
+			}
+			if got.InclusionFeeCharged.Int64+nonRefundable+refundable != feeCharged {
+				t.Errorf("inclusion fee charged + resource fee charged (%d) does not reconcile with FeeCharged (%d)",
+					got.InclusionFeeCharged.Int64+nonRefundable+refundable, feeCharged)
+			}
+			if got.ContractEventsCount != int32(len(sorobanMeta.Events)) {
+				t.Errorf("ContractEventsCount = %d, want %d", got.ContractEventsCount, len(sorobanMeta.Events))
+			}
+		})
+	}
+}
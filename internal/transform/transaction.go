@@ -180,6 +180,46 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 		transformedTransaction.NewMaxFee = uint32(transaction.Envelope.FeeBumpFee())
 	}
 
+	// Add Soroban details, if this is a Protocol 20+ Soroban transaction
+	if sorobanData, ok := sorobanTransactionData(transaction.Envelope); ok {
+		resourceFee := int64(sorobanData.ResourceFee)
+		transformedTransaction.ResourceFee = null.IntFrom(resourceFee)
+		transformedTransaction.InclusionFeeBid = null.IntFrom(sorobanTransactionMaxFee(transaction.Envelope) - resourceFee)
+
+		resources := sorobanData.Resources
+		transformedTransaction.SorobanResourcesInstructions = null.IntFrom(int64(resources.Instructions))
+		transformedTransaction.SorobanResourcesReadBytes = null.IntFrom(int64(resources.ReadBytes))
+		transformedTransaction.SorobanResourcesWriteBytes = null.IntFrom(int64(resources.WriteBytes))
+
+		outputTxData, err := xdr.MarshalBase64(&sorobanData)
+		if err != nil {
+			return TransactionOutput{}, err
+		}
+		transformedTransaction.TransactionDataXDR = outputTxData
+
+		if sorobanMeta, ok := sorobanTransactionMeta(transaction.UnsafeMeta); ok {
+			nonRefundable, refundable := sorobanRefundableFees(sorobanMeta)
+			transformedTransaction.RefundableFeeCharged = null.IntFrom(refundable)
+			transformedTransaction.InclusionFeeCharged = null.IntFrom(outputFeeCharged - nonRefundable - refundable)
+			transformedTransaction.ContractEventsCount = int32(len(sorobanMeta.Events))
+
+			diagnosticEvents := make(pq.StringArray, len(sorobanMeta.DiagnosticEvents))
+			for i, event := range sorobanMeta.DiagnosticEvents {
+				encoded, err := xdr.MarshalBase64(&event)
+				if err != nil {
+					return TransactionOutput{}, err
+				}
+				diagnosticEvents[i] = encoded
+			}
+			transformedTransaction.DiagnosticEventsXDR = diagnosticEvents
+		} else {
+			// The transaction never made it to the apply phase (e.g. it
+			// failed during validation), so there's no resource fee actually
+			// charged to account for; the whole resource fee bid was refunded.
+			transformedTransaction.InclusionFeeCharged = null.IntFrom(outputFeeCharged)
+		}
+	}
+
 	return transformedTransaction, nil
 }
 
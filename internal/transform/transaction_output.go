@@ -0,0 +1,54 @@
+package transform
+
+import (
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+)
+
+// TransactionOutput is a transaction transformed for ingestion by BigQuery
+// and the other output sinks in internal/output.
+type TransactionOutput struct {
+	TransactionHash             string         `json:"transaction_hash"`
+	LedgerSequence              uint32         `json:"ledger_sequence"`
+	TransactionID               int64          `json:"transaction_id"`
+	Account                     string         `json:"account"`
+	AccountMuxed                string         `json:"account_muxed,omitempty"`
+	AccountSequence             int64          `json:"account_sequence"`
+	MaxFee                      int64          `json:"max_fee"`
+	FeeCharged                  int64          `json:"fee_charged"`
+	OperationCount              int32          `json:"operation_count"`
+	TxEnvelope                  string         `json:"tx_envelope"`
+	TxResult                    string         `json:"tx_result"`
+	TxMeta                      string         `json:"tx_meta"`
+	TxFeeMeta                   string         `json:"tx_fee_meta"`
+	CreatedAt                   time.Time      `json:"created_at"`
+	MemoType                    string         `json:"memo_type"`
+	Memo                        string         `json:"memo"`
+	TimeBounds                  string         `json:"time_bounds"`
+	Successful                  bool           `json:"successful"`
+	LedgerBounds                string         `json:"ledger_bounds"`
+	MinAccountSequence          null.Int       `json:"min_account_sequence"`
+	MinAccountSequenceAge       null.Int       `json:"min_account_sequence_age"`
+	MinAccountSequenceLedgerGap null.Int       `json:"min_account_sequence_ledger_gap"`
+	ExtraSigners                pq.StringArray `json:"extra_signers"`
+	FeeAccount                  string         `json:"fee_account,omitempty"`
+	FeeAccountMuxed             string         `json:"fee_account_muxed,omitempty"`
+	InnerTransactionHash        string         `json:"inner_transaction_hash,omitempty"`
+	NewMaxFee                   uint32         `json:"new_max_fee,omitempty"`
+
+	// Soroban / Protocol 20 fields. These are only populated for
+	// transactions that carry a SorobanTransactionData extension on the
+	// envelope; they're left at their zero value otherwise.
+	ResourceFee                  null.Int       `json:"resource_fee,omitempty"`
+	SorobanResourcesInstructions null.Int       `json:"soroban_resources_instructions,omitempty"`
+	SorobanResourcesReadBytes    null.Int       `json:"soroban_resources_read_bytes,omitempty"`
+	SorobanResourcesWriteBytes   null.Int       `json:"soroban_resources_write_bytes,omitempty"`
+	InclusionFeeBid              null.Int       `json:"inclusion_fee_bid,omitempty"`
+	InclusionFeeCharged          null.Int       `json:"inclusion_fee_charged,omitempty"`
+	RefundableFeeCharged         null.Int       `json:"refundable_fee_charged,omitempty"`
+	ContractEventsCount          int32          `json:"contract_events_count"`
+	DiagnosticEventsXDR          pq.StringArray `json:"diagnostic_events_xdr,omitempty"`
+	TransactionDataXDR           string         `json:"transaction_data_xdr,omitempty"`
+}
@@ -0,0 +1,244 @@
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+	"github.com/stellar/stellar-etl/internal/transform"
+)
+
+// Report is the structured result of comparing a batch of exported ledger
+// entries against the history archive bucket list at a checkpoint ledger.
+type Report struct {
+	CheckpointLedger uint32       `json:"checkpoint_ledger"`
+	Types            []TypeReport `json:"types"`
+	Diverged         bool         `json:"diverged"`
+}
+
+// TypeReport is the per-LedgerEntryType rollup within a Report.
+type TypeReport struct {
+	EntryType    string `json:"entry_type"`
+	ExportedRoot string `json:"exported_root"`
+	ArchiveRoot  string `json:"archive_root"`
+	ExportedSize int    `json:"exported_count"`
+	ArchiveSize  int    `json:"archive_count"`
+	Match        bool   `json:"match"`
+}
+
+// transformer converts a single archive change into the same representation
+// the exporter would have produced for it, so the two sides can be hashed and
+// compared like-for-like.
+type transformer func(change ingest.Change) (entry interface{}, err error)
+
+// verifiers lists the entry types VerifyState participates in, each paired
+// with the transform that turns an archive Change into the exporter's shape.
+// Adding a new exported entry type needs an entry here, one in
+// entryTypeNames, and one in VerifyState's exportedByType.
+var verifiers = map[xdr.LedgerEntryType]transformer{
+	xdr.LedgerEntryTypeAccount: func(change ingest.Change) (interface{}, error) {
+		return transform.TransformAccount(change)
+	},
+	xdr.LedgerEntryTypeOffer: func(change ingest.Change) (interface{}, error) {
+		return transform.TransformOffer(change)
+	},
+	xdr.LedgerEntryTypeTrustline: func(change ingest.Change) (interface{}, error) {
+		return transform.TransformTrustline(change)
+	},
+	xdr.LedgerEntryTypeLiquidityPool: func(change ingest.Change) (interface{}, error) {
+		return transform.TransformPool(change)
+	},
+	xdr.LedgerEntryTypeClaimableBalance: func(change ingest.Change) (interface{}, error) {
+		return transform.TransformClaimableBalance(change)
+	},
+}
+
+var entryTypeNames = map[xdr.LedgerEntryType]string{
+	xdr.LedgerEntryTypeAccount:          "accounts",
+	xdr.LedgerEntryTypeOffer:            "offers",
+	xdr.LedgerEntryTypeTrustline:        "trustlines",
+	xdr.LedgerEntryTypeLiquidityPool:    "liquidity_pools",
+	xdr.LedgerEntryTypeClaimableBalance: "claimable_balances",
+}
+
+// toInterfaceSlice copies a concrete []T (e.g. []transform.AccountOutput)
+// into a []interface{} so it can be rolled up by entryRollup alongside the
+// archive side, which transforms each change to interface{} as it streams.
+// Go won't convert []T to []interface{} directly, so this has to walk the
+// slice with reflection - the same reason the Postgres/SQLite sinks iterate
+// TransformedOutputType's fields by reflect.Value instead of asserting them.
+func toInterfaceSlice(slice interface{}) []interface{} {
+	v := reflect.ValueOf(slice)
+	out := make([]interface{}, v.Len())
+	for i := range out {
+		out[i] = v.Index(i).Interface()
+	}
+	return out
+}
+
+// VerifyState streams the bucketlist snapshot for checkpointLedger from
+// archive, keeping only the entries last modified exactly at checkpointLedger
+// so the archive side and exported represent the same universe: exported
+// (see export_ledger_entry_changes.go's verifyOutputs) only ever holds the
+// entries whose LastModifiedLedgerSeq landed on that checkpoint, and hashing
+// the archive's entire bucket list against that subset would flag everything
+// else on the network as a divergence. Each side is rolled up into one hash
+// per LedgerEntryType, batchSize entries at a time, so readCheckpoint never
+// has to hold the whole checkpoint in memory at once.
+func VerifyState(ctx context.Context, exported transform.TransformedOutputType, archive historyarchive.ArchiveInterface, checkpointLedger uint32, batchSize int) (Report, error) {
+	archiveRollups, err := readCheckpoint(ctx, archive, checkpointLedger, batchSize)
+	if err != nil {
+		return Report{}, fmt.Errorf("reading checkpoint %d from the history archive: %v", checkpointLedger, err)
+	}
+
+	exportedByType := map[xdr.LedgerEntryType][]interface{}{
+		xdr.LedgerEntryTypeAccount:          toInterfaceSlice(exported.Accounts),
+		xdr.LedgerEntryTypeOffer:            toInterfaceSlice(exported.Offers),
+		xdr.LedgerEntryTypeTrustline:        toInterfaceSlice(exported.Trustlines),
+		xdr.LedgerEntryTypeLiquidityPool:    toInterfaceSlice(exported.Liquidity_pools),
+		xdr.LedgerEntryTypeClaimableBalance: toInterfaceSlice(exported.Claimable_balances),
+	}
+
+	report := Report{CheckpointLedger: checkpointLedger}
+	for entryType, name := range entryTypeNames {
+		exportedRollup := newEntryRollup()
+		for _, entry := range exportedByType[entryType] {
+			if err := exportedRollup.add(entry); err != nil {
+				return Report{}, fmt.Errorf("hashing exported %s: %v", name, err)
+			}
+		}
+
+		archiveRollup := archiveRollups[entryType]
+		if archiveRollup == nil {
+			archiveRollup = newEntryRollup()
+		}
+
+		typeReport := TypeReport{
+			EntryType:    name,
+			ExportedRoot: exportedRollup.root(),
+			ArchiveRoot:  archiveRollup.root(),
+			ExportedSize: exportedRollup.count,
+			ArchiveSize:  archiveRollup.count,
+			Match:        exportedRollup.count == archiveRollup.count && exportedRollup.root() == archiveRollup.root(),
+		}
+		report.Types = append(report.Types, typeReport)
+		if !typeReport.Match {
+			report.Diverged = true
+		}
+	}
+	sort.Slice(report.Types, func(i, j int) bool { return report.Types[i].EntryType < report.Types[j].EntryType })
+
+	return report, nil
+}
+
+// readCheckpoint streams every change in the checkpoint bucketlist in
+// batches of batchSize, keeping only the entries VerifyState cares about
+// that were last modified exactly at checkpointLedger - the same slice of
+// state the exporter's verifyOutputs accumulates for that checkpoint - and
+// folds each one into a running per-type hash as it's read, rather than
+// buffering the whole checkpoint in memory.
+func readCheckpoint(ctx context.Context, archive historyarchive.ArchiveInterface, checkpointLedger uint32, batchSize int) (map[xdr.LedgerEntryType]*entryRollup, error) {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	reader, err := ingest.NewCheckpointChangeReader(ctx, archive, checkpointLedger)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	rollups := make(map[xdr.LedgerEntryType]*entryRollup)
+	batch := make([]ingest.Change, 0, batchSize)
+
+	flush := func() error {
+		for _, change := range batch {
+			transformChange, ok := verifiers[change.Type]
+			if !ok {
+				continue
+			}
+			if change.Post == nil || uint32(change.Post.LastModifiedLedgerSeq) != checkpointLedger {
+				continue
+			}
+
+			entry, err := transformChange(change)
+			if err != nil {
+				continue
+			}
+
+			rollup, ok := rollups[change.Type]
+			if !ok {
+				rollup = newEntryRollup()
+				rollups[change.Type] = rollup
+			}
+			if err := rollup.add(entry); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		change, err := reader.Read()
+		if err == ingest.ErrEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		batch = append(batch, change)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return rollups, nil
+}
+
+// entryRollup folds a stream of entries into a single hash by XORing each
+// entry's own hash into a running accumulator, plus a count. XOR is
+// commutative and associative, so the root doesn't depend on the order
+// entries arrive in or on how they're split across batches - unlike a
+// sorted-concatenation rollup, entries never need to be held in memory
+// together to be compared. XOR alone can't distinguish a set from one with
+// an evenly-duplicated entry added (the duplicate cancels out), so callers
+// comparing two rollups must also compare count - see VerifyState's Match.
+type entryRollup struct {
+	hash  [sha256.Size]byte
+	count int
+}
+
+func newEntryRollup() *entryRollup {
+	return &entryRollup{}
+}
+
+func (r *entryRollup) add(entry interface{}) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(raw)
+	for i := range r.hash {
+		r.hash[i] ^= sum[i]
+	}
+	r.count++
+	return nil
+}
+
+func (r *entryRollup) root() string {
+	return hex.EncodeToString(r.hash[:])
+}
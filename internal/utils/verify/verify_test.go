@@ -0,0 +1,59 @@
+package verify
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestToInterfaceSlice guards VerifyState's use of toInterfaceSlice to
+// bridge TransformedOutputType's concrete typed slices (e.g.
+// []transform.AccountOutput) into the []interface{} entryRollup expects -
+// assigning a typed slice straight into a map[...][]interface{} doesn't
+// compile, which is exactly the bug this function works around.
+func TestToInterfaceSlice(t *testing.T) {
+	type entry struct{ ID string }
+
+	got := toInterfaceSlice([]entry{{ID: "A"}, {ID: "B"}})
+	want := []interface{}{entry{ID: "A"}, entry{ID: "B"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toInterfaceSlice = %#v, want %#v", got, want)
+	}
+}
+
+// TestEntryRollupDuplicateCancelsXORButNotCount guards the scenario the
+// Match field has to catch: XORing an entry into a rollup twice cancels it
+// out of the hash, so a root-only comparison can't tell {A} apart from
+// {A, B, B}. Count must be compared alongside the root (see VerifyState).
+func TestEntryRollupDuplicateCancelsXORButNotCount(t *testing.T) {
+	type entry struct {
+		ID string `json:"id"`
+	}
+
+	exported := newEntryRollup()
+	if err := exported.add(entry{ID: "A"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	archive := newEntryRollup()
+	if err := archive.add(entry{ID: "A"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := archive.add(entry{ID: "B"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := archive.add(entry{ID: "B"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	if exported.root() != archive.root() {
+		t.Fatalf("expected the duplicate B's to cancel out of the XOR root, roots differ")
+	}
+	if exported.count == archive.count {
+		t.Fatalf("expected counts to differ (%d vs %d)", exported.count, archive.count)
+	}
+
+	match := exported.count == archive.count && exported.root() == archive.root()
+	if match {
+		t.Fatalf("root-only comparison would report a match despite a 1-vs-3 count divergence")
+	}
+}